@@ -0,0 +1,195 @@
+package hystrix
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the lifecycle state of a Circuit.
+type CircuitState int
+
+const (
+	// StateClosed allows requests through and trips to StateOpen once the
+	// rolling error rate crosses the configured threshold.
+	StateClosed CircuitState = iota
+	// StateOpen short-circuits every request straight to fallback.
+	StateOpen
+	// StateHalfOpen has let a single probe request through to test
+	// whether the dependency has recovered.
+	StateHalfOpen
+)
+
+// String renders the state the way it's reported over the event stream
+// (see NewStreamHandler).
+func (s CircuitState) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Circuit tracks the rolling success/failure counts for a single named pool
+// and decides, Hystrix-style, whether requests should be allowed through,
+// short-circuited, or treated as a recovery probe.
+type Circuit struct {
+	Name string
+
+	mutex    sync.Mutex
+	state    CircuitState
+	openedAt time.Time
+	window   *rollingWindow
+	config   CommandConfig
+}
+
+func newCircuit(name string) *Circuit {
+	return &Circuit{
+		Name:   name,
+		window: newRollingWindow(),
+		config: getSettings(name),
+	}
+}
+
+// IsOpen reports whether the circuit is currently short-circuiting
+// requests. While open it allows exactly one probe through once
+// SleepWindow has elapsed, moving itself to StateHalfOpen for that single
+// caller until the probe reports back.
+func (c *Circuit) IsOpen() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch c.state {
+	case StateClosed:
+		return false
+	case StateHalfOpen:
+		return true
+	default: // StateOpen
+		if time.Since(c.openedAt) < c.config.SleepWindow {
+			return true
+		}
+		c.state = StateHalfOpen
+		return false
+	}
+}
+
+// ReportSuccess records a completed request and, if it was the half-open
+// probe, closes the circuit and resets the rolling window. Otherwise it
+// may still trip the circuit: a success is what pushes the window's total
+// past RequestVolumeThreshold just as often as a failure does, and the
+// window's error percentage is unaffected by which kind of request
+// happened to be the one that crossed that line.
+func (c *Circuit) ReportSuccess() {
+	c.window.record(func(b *bucket) { b.successes++ })
+	c.closeIfHalfOpen()
+	c.tripIfUnhealthy()
+}
+
+// ReportFailure records a request that returned an error. If it was the
+// half-open probe, the circuit re-opens and its sleep timer restarts;
+// otherwise it may trip the circuit if the rolling window is now unhealthy.
+func (c *Circuit) ReportFailure() {
+	c.window.record(func(b *bucket) { b.failures++ })
+	c.reopenIfHalfOpenElse(c.tripIfUnhealthy)
+}
+
+// ReportTimeout records a request that exceeded Runner.Timeout().
+func (c *Circuit) ReportTimeout() {
+	c.window.record(func(b *bucket) { b.timeouts++ })
+	c.reopenIfHalfOpenElse(c.tripIfUnhealthy)
+}
+
+// ReportRejection records a request that could not get an executor because
+// the pool was full. If it was the half-open probe, the circuit re-opens
+// and its sleep timer restarts - a rejected probe tells us nothing about
+// recovery, so treat it like a failed one rather than leaving the circuit
+// wedged in StateHalfOpen; otherwise it may trip the circuit if the
+// rolling window is now unhealthy.
+func (c *Circuit) ReportRejection() {
+	c.window.record(func(b *bucket) { b.rejections++ })
+	c.reopenIfHalfOpenElse(c.tripIfUnhealthy)
+}
+
+// ReportShortCircuit records a request that was turned away because the
+// circuit was already open. It exists for observability only and does not
+// affect the error percentage used to trip the circuit.
+func (c *Circuit) ReportShortCircuit() {
+	c.window.record(func(b *bucket) { b.shortCircuits++ })
+}
+
+// ReportCancellation records a request whose caller-supplied context was
+// cancelled before a result arrived. A cancellation reflects the caller
+// giving up rather than the dependency misbehaving, so on its own it is
+// tracked for observability only and does not count towards the error
+// percentage used to trip the circuit. But if it was the half-open probe,
+// a cancelled probe still tells us nothing about recovery, so the circuit
+// re-opens and its sleep timer restarts the same as a failed probe would -
+// otherwise a rejected or cancelled probe would leave the circuit wedged
+// in StateHalfOpen forever.
+func (c *Circuit) ReportCancellation() {
+	c.window.record(func(b *bucket) { b.cancellations++ })
+	c.reopenIfHalfOpenElse(func() {})
+}
+
+func (c *Circuit) closeIfHalfOpen() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.state == StateHalfOpen {
+		c.state = StateClosed
+		c.window.reset()
+	}
+}
+
+// reopenIfHalfOpenElse re-opens the circuit if the failing request was the
+// half-open probe; otherwise it runs the given fallback check (typically
+// tripIfUnhealthy).
+func (c *Circuit) reopenIfHalfOpenElse(fallback func()) {
+	c.mutex.Lock()
+	if c.state == StateHalfOpen {
+		c.open()
+		c.mutex.Unlock()
+		return
+	}
+	c.mutex.Unlock()
+	fallback()
+}
+
+// tripIfUnhealthy opens the circuit once RequestVolumeThreshold requests
+// have been seen in the rolling window and the error percentage exceeds
+// ErrorPercentThreshold.
+func (c *Circuit) tripIfUnhealthy() {
+	snap := c.window.snapshot()
+	total := snap.total()
+	if total < c.config.RequestVolumeThreshold {
+		return
+	}
+	errorPct := float64(snap.errors()) * 100 / float64(total)
+	if errorPct <= float64(c.config.ErrorPercentThreshold) {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.state != StateOpen {
+		c.open()
+	}
+}
+
+// stateSnapshot returns the circuit's current state together with the
+// rolling window's totals, for reporting over the event stream.
+func (c *Circuit) stateSnapshot() (CircuitState, bucket) {
+	c.mutex.Lock()
+	state := c.state
+	c.mutex.Unlock()
+	return state, c.window.snapshot()
+}
+
+// open transitions the circuit to StateOpen, stamping the time used to
+// measure SleepWindow. Callers must hold c.mutex.
+func (c *Circuit) open() {
+	c.state = StateOpen
+	c.openedAt = time.Now()
+	c.window.reset()
+}
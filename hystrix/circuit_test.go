@@ -0,0 +1,165 @@
+package hystrix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitTripsOnErrorPercentage(t *testing.T) {
+	name := "circuit-test-trip"
+	ConfigureCommand(name, CommandConfig{
+		RequestVolumeThreshold: 4,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            50 * time.Millisecond,
+	})
+	circuit := newCircuit(name)
+
+	if circuit.IsOpen() {
+		t.Fatal("circuit should start closed")
+	}
+
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	circuit.ReportSuccess()
+
+	if !circuit.IsOpen() {
+		t.Fatal("circuit should trip once RequestVolumeThreshold and ErrorPercentThreshold are both met")
+	}
+}
+
+func TestCircuitStaysClosedBelowRequestVolumeThreshold(t *testing.T) {
+	name := "circuit-test-below-volume"
+	ConfigureCommand(name, CommandConfig{
+		RequestVolumeThreshold: 10,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            50 * time.Millisecond,
+	})
+	circuit := newCircuit(name)
+
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+
+	if circuit.IsOpen() {
+		t.Fatal("circuit should not trip before RequestVolumeThreshold requests are seen, even at 100% errors")
+	}
+}
+
+func TestCircuitHalfOpenProbeRecovers(t *testing.T) {
+	name := "circuit-test-half-open-recover"
+	ConfigureCommand(name, CommandConfig{
+		RequestVolumeThreshold: 2,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            10 * time.Millisecond,
+	})
+	circuit := newCircuit(name)
+
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	if !circuit.IsOpen() {
+		t.Fatal("circuit should be open after tripping")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if circuit.IsOpen() {
+		t.Fatal("circuit should allow a single half-open probe through once SleepWindow elapses")
+	}
+	if !circuit.IsOpen() {
+		t.Fatal("circuit should short-circuit further callers while the probe is in flight")
+	}
+
+	circuit.ReportSuccess()
+	if circuit.IsOpen() {
+		t.Fatal("a successful probe should close the circuit and allow requests through again")
+	}
+}
+
+func TestCircuitHalfOpenProbeFailureReopens(t *testing.T) {
+	name := "circuit-test-half-open-reopen"
+	ConfigureCommand(name, CommandConfig{
+		RequestVolumeThreshold: 2,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            10 * time.Millisecond,
+	})
+	circuit := newCircuit(name)
+
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	if !circuit.IsOpen() {
+		t.Fatal("circuit should be open after tripping")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if circuit.IsOpen() {
+		t.Fatal("circuit should allow the half-open probe through")
+	}
+
+	circuit.ReportFailure()
+	if !circuit.IsOpen() {
+		t.Fatal("a failed probe should re-open the circuit")
+	}
+}
+
+func TestCircuitHalfOpenProbeRejectionReopensInsteadOfWedging(t *testing.T) {
+	name := "circuit-test-half-open-rejection"
+	ConfigureCommand(name, CommandConfig{
+		RequestVolumeThreshold: 2,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            10 * time.Millisecond,
+	})
+	circuit := newCircuit(name)
+
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	time.Sleep(20 * time.Millisecond)
+
+	if circuit.IsOpen() {
+		t.Fatal("circuit should allow the half-open probe through")
+	}
+
+	// The probe itself gets rejected (e.g. the pool was momentarily full)
+	// rather than succeeding or failing outright.
+	circuit.ReportRejection()
+
+	if !circuit.IsOpen() {
+		t.Fatal("a rejected probe should re-open the circuit, not leave it wedged in half-open")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if circuit.IsOpen() {
+		t.Fatal("circuit should allow another probe through once SleepWindow elapses again")
+	}
+}
+
+func TestCircuitHalfOpenProbeCancellationReopensInsteadOfWedging(t *testing.T) {
+	name := "circuit-test-half-open-cancellation"
+	ConfigureCommand(name, CommandConfig{
+		RequestVolumeThreshold: 2,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            10 * time.Millisecond,
+	})
+	circuit := newCircuit(name)
+
+	circuit.ReportFailure()
+	circuit.ReportFailure()
+	time.Sleep(20 * time.Millisecond)
+
+	if circuit.IsOpen() {
+		t.Fatal("circuit should allow the half-open probe through")
+	}
+
+	// The probe's caller cancelled before a result arrived.
+	circuit.ReportCancellation()
+
+	if !circuit.IsOpen() {
+		t.Fatal("a cancelled probe should re-open the circuit, not leave it wedged in half-open")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if circuit.IsOpen() {
+		t.Fatal("circuit should allow another probe through once SleepWindow elapses again")
+	}
+}
@@ -1,7 +1,10 @@
 package hystrix
 
-import "time"
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 // Command is the core struct for hystrix execution.  It maps the user-defined
 // Runner with channels for delivering results.
@@ -28,59 +31,176 @@ func NewCommand(runner Runner) *Command {
 	command.Runner = runner
 	command.ResultChannel = make(chan Result, 1)
 	command.FallbackChannel = make(chan Result, 1)
-	command.ExecutorPool = NewExecutorPool(runner.PoolName(), 10)
+	command.ExecutorPool = NewExecutorPool(runner.PoolName())
 
 	return command
 }
 
 // Execute runs the command synchronously, blocking until the result (or fallback) is returned
 func (command *Command) Execute() Result {
-	channel := command.Queue()
+	return command.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext runs the command synchronously like Execute, but aborts
+// early if ctx is cancelled before a result is available: the command's
+// fallback is invoked with ctx.Err() instead.
+func (command *Command) ExecuteWithContext(ctx context.Context) Result {
+	channel := make(chan Result, 1)
+	command.tryRun(ctx, channel)
 	return <-channel
 }
 
 // Queue runs the command asynchronously, immediately returning a channel which the result (or fallback) will be sent to.
 func (command *Command) Queue() chan Result {
+	return command.QueueWithContext(context.Background())
+}
+
+// QueueWithContext runs the command asynchronously like Queue, additionally
+// watching ctx so a cancelled caller aborts the wait instead of blocking
+// until the Runner's own Timeout() fires.
+func (command *Command) QueueWithContext(ctx context.Context) chan Result {
 	channel := make(chan Result, 1)
-	go command.tryRun(channel)
+	go command.tryRun(ctx, channel)
 	return channel
 }
 
-func (command *Command) tryRun(valueChannel chan Result) {
+func (command *Command) tryRun(ctx context.Context, valueChannel chan Result) {
 	defer close(valueChannel)
-	if command.ExecutorPool.Circuit.IsOpen() {
+	start := time.Now()
+	circuit := command.ExecutorPool.Circuit
+	if circuit.IsOpen() {
 		// fallback if circuit is open due to too many recent failures
+		circuit.ReportShortCircuit()
+		command.publish(EventShortCircuit, start)
 		valueChannel <- command.tryFallback(errors.New("circuit open"))
+		return
+	}
+
+	if command.ExecutorPool.IsolationStrategy == SemaphoreIsolation {
+		command.trySemaphore(ctx, valueChannel, circuit, start)
 	} else {
+		command.tryThread(ctx, valueChannel, circuit, start)
+	}
+}
+
+// tryThread runs the command on its own goroutine drawn from the
+// ExecutorPool, enforcing Runner.Timeout() with a watchdog select alongside
+// the result channel and ctx.Done(). This is Hystrix's "thread" isolation.
+func (command *Command) tryThread(ctx context.Context, valueChannel chan Result, circuit *Circuit, start time.Time) {
+	select {
+	case executor := <-command.ExecutorPool.Executors:
+		defer func() {
+			command.ExecutorPool.Executors <- executor
+		}()
+
+		go executor.RunContext(ctx, command)
+
 		select {
-		case executor := <-command.ExecutorPool.Executors:
-			defer func() {
-				command.ExecutorPool.Executors <- executor
-			}()
-
-			go executor.Run(command)
-
-			select {
-			case result := <-command.ResultChannel:
-				if result.Error != nil {
-					// fallback if run fails
-					valueChannel <- command.tryFallback(result.Error)
-				} else {
-					valueChannel <- result
-				}
-			case <-time.After(command.Runner.Timeout()):
-				// fallback if timeout is reached
-				valueChannel <- command.tryFallback(errors.New("timeout"))
+		case result := <-command.ResultChannel:
+			if result.Error != nil {
+				// fallback if run fails
+				circuit.ReportFailure()
+				command.publish(EventFailure, start)
+				valueChannel <- command.tryFallback(result.Error)
+			} else {
+				circuit.ReportSuccess()
+				command.publish(EventSuccess, start)
+				valueChannel <- result
 			}
-		default:
-			// fallback if executor pool is full
-			valueChannel <- command.tryFallback(errors.New("executor pool full"))
+		case <-time.After(command.Runner.Timeout()):
+			// fallback if timeout is reached
+			circuit.ReportTimeout()
+			command.publish(EventTimeout, start)
+			valueChannel <- command.tryFallback(errors.New("timeout"))
+		case <-ctx.Done():
+			// fallback if the caller's context is cancelled first
+			circuit.ReportCancellation()
+			command.publish(EventCancellation, start)
+			valueChannel <- command.tryFallback(ctx.Err())
 		}
+	default:
+		// fallback if executor pool is full
+		circuit.ReportRejection()
+		command.publish(EventRejected, start)
+		valueChannel <- command.tryFallback(errors.New("executor pool full"))
+	}
+}
+
+// trySemaphore bounds concurrency with a counting semaphore instead of
+// checking an executor out of a goroutine pool, which is cheaper for
+// in-process callbacks than tryThread's dedicated pool - it does not
+// enforce Runner.Timeout() with a watchdog case either. When ctx can never
+// be cancelled (e.g. context.Background(), the common case), the Runner
+// runs truly inline on the caller's own goroutine with no extra goroutine
+// spawn (for Queue, that is the one goroutine Queue itself starts). Only a genuinely cancelable ctx pays for a goroutine, so its
+// Done() can be raced against the result. This is Hystrix's "semaphore"
+// isolation.
+func (command *Command) trySemaphore(ctx context.Context, valueChannel chan Result, circuit *Circuit, start time.Time) {
+	select {
+	case token := <-command.ExecutorPool.Executors:
+		defer func() {
+			command.ExecutorPool.Executors <- token
+		}()
+
+		if ctx.Done() == nil {
+			token.RunContext(ctx, command)
+			command.reportSemaphoreResult(<-command.ResultChannel, valueChannel, circuit, start)
+			return
+		}
+
+		go token.RunContext(ctx, command)
+
+		select {
+		case result := <-command.ResultChannel:
+			command.reportSemaphoreResult(result, valueChannel, circuit, start)
+		case <-ctx.Done():
+			// fallback if the caller's context is cancelled first
+			circuit.ReportCancellation()
+			command.publish(EventCancellation, start)
+			valueChannel <- command.tryFallback(ctx.Err())
+		}
+	default:
+		// fallback if the semaphore is fully checked out
+		circuit.ReportRejection()
+		command.publish(EventRejected, start)
+		valueChannel <- command.tryFallback(errors.New("semaphore rejected"))
+	}
+}
+
+// reportSemaphoreResult records the circuit/metric outcome of a completed
+// semaphore-isolated run and delivers its result (or fallback) downstream.
+func (command *Command) reportSemaphoreResult(result Result, valueChannel chan Result, circuit *Circuit, start time.Time) {
+	if result.Error != nil {
+		// fallback if run fails
+		circuit.ReportFailure()
+		command.publish(EventFailure, start)
+		valueChannel <- command.tryFallback(result.Error)
+	} else {
+		circuit.ReportSuccess()
+		command.publish(EventSuccess, start)
+		valueChannel <- result
 	}
 }
 
 func (command *Command) tryFallback(err error) Result {
+	start := time.Now()
 	go command.Runner.Fallback(err, command.FallbackChannel)
 	// TODO: implement case for if fallback never returns
-	return <-command.FallbackChannel
+	result := <-command.FallbackChannel
+	if result.Error != nil {
+		command.publish(EventFallbackFailure, start)
+	} else {
+		command.publish(EventFallbackSuccess, start)
+	}
+	return result
+}
+
+// publish notifies every registered MetricCollector that this command's
+// pool just produced eventType, with latency measured since start.
+func (command *Command) publish(eventType MetricEventType, start time.Time) {
+	publishMetric(MetricEvent{
+		Type:     eventType,
+		PoolName: command.ExecutorPool.Name,
+		Latency:  time.Since(start),
+	})
 }
@@ -0,0 +1,54 @@
+package hystrix
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type contextTestRunner struct {
+	name string
+}
+
+func (r *contextTestRunner) Run(result chan Result) {
+	time.Sleep(200 * time.Millisecond)
+	result <- Result{Value: "too slow"}
+}
+
+func (r *contextTestRunner) Fallback(err error, result chan Result) {
+	result <- Result{Error: err}
+}
+
+func (r *contextTestRunner) PoolName() string       { return r.name }
+func (r *contextTestRunner) Timeout() time.Duration { return time.Second }
+
+func TestExecuteWithContextCancellationShortCircuitsToFallback(t *testing.T) {
+	command := NewCommand(&contextTestRunner{name: "context-test-cancel"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result := command.ExecuteWithContext(ctx)
+
+	if !errors.Is(result.Error, context.DeadlineExceeded) {
+		t.Fatalf("expected the fallback to see context.DeadlineExceeded, got %v", result.Error)
+	}
+}
+
+func TestQueueWithContextReturnsPromptlyOnCancellation(t *testing.T) {
+	command := NewCommand(&contextTestRunner{name: "context-test-queue-cancel"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	channel := command.QueueWithContext(ctx)
+	cancel()
+
+	select {
+	case result := <-channel:
+		if !errors.Is(result.Error, context.Canceled) {
+			t.Fatalf("expected the fallback to see context.Canceled, got %v", result.Error)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("cancelling ctx should abort the wait well before Runner.Timeout() or Run() return")
+	}
+}
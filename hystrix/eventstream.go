@@ -0,0 +1,165 @@
+package hystrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PoolSnapshot is a single pool's point-in-time statistics, as emitted by
+// StreamHandler.
+type PoolSnapshot struct {
+	Name              string  `json:"name"`
+	CircuitState      string  `json:"circuit_state"`
+	Requests          uint64  `json:"requests"`
+	Successes         uint64  `json:"successes"`
+	Failures          uint64  `json:"failures"`
+	Timeouts          uint64  `json:"timeouts"`
+	Rejections        uint64  `json:"rejections"`
+	ShortCircuits     uint64  `json:"short_circuits"`
+	ErrorPercent      float64 `json:"error_percent"`
+	ExecutorPoolSize  int     `json:"executor_pool_size"`
+	ExecutorPoolInUse int     `json:"executor_pool_in_use"`
+	MeanLatencyMs     float64 `json:"mean_latency_ms"`
+	P99LatencyMs      float64 `json:"p99_latency_ms"`
+}
+
+const latencyWindowSize = 256
+
+// latencyCollector is the MetricCollector NewStreamHandler registers on a
+// caller's behalf so the stream can report mean/p99 latency per pool
+// without requiring its own instrumentation.
+type latencyCollector struct {
+	mutex   sync.Mutex
+	samples map[string][]time.Duration
+}
+
+func newLatencyCollector() *latencyCollector {
+	return &latencyCollector{samples: make(map[string][]time.Duration)}
+}
+
+func (l *latencyCollector) Update(event MetricEvent) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	buf := append(l.samples[event.PoolName], event.Latency)
+	if len(buf) > latencyWindowSize {
+		buf = buf[len(buf)-latencyWindowSize:]
+	}
+	l.samples[event.PoolName] = buf
+}
+
+// meanAndP99 computes the mean and 99th-percentile latency over the
+// samples currently held for poolName.
+func (l *latencyCollector) meanAndP99(poolName string) (mean, p99 time.Duration) {
+	l.mutex.Lock()
+	buf := append([]time.Duration(nil), l.samples[poolName]...)
+	l.mutex.Unlock()
+
+	if len(buf) == 0 {
+		return 0, 0
+	}
+
+	var sum time.Duration
+	for _, d := range buf {
+		sum += d
+	}
+	mean = sum / time.Duration(len(buf))
+
+	sort.Slice(buf, func(i, j int) bool { return buf[i] < buf[j] })
+	index := int(float64(len(buf)) * 0.99)
+	if index >= len(buf) {
+		index = len(buf) - 1
+	}
+	p99 = buf[index]
+	return mean, p99
+}
+
+// StreamHandler serves a text/event-stream of per-pool JSON snapshots -
+// rolling counts, error percentage, circuit state, executor pool
+// utilisation, and mean/p99 latency - so dashboards can scrape the package's
+// state without the caller writing any instrumentation of their own.
+type StreamHandler struct {
+	interval time.Duration
+	latency  *latencyCollector
+}
+
+// NewStreamHandler builds a StreamHandler that emits a fresh snapshot of
+// every known pool every interval. It registers its own MetricCollector to
+// track latency.
+func NewStreamHandler(interval time.Duration) *StreamHandler {
+	handler := &StreamHandler{
+		interval: interval,
+		latency:  newLatencyCollector(),
+	}
+	RegisterMetricCollector(handler.latency)
+	return handler
+}
+
+// ServeHTTP streams one JSON snapshot per known pool, every interval, until
+// the request is cancelled.
+func (h *StreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			for _, snapshot := range h.snapshots() {
+				payload, err := json.Marshal(snapshot)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "data: %s\n\n", payload)
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *StreamHandler) snapshots() []PoolSnapshot {
+	poolMutex.RLock()
+	defer poolMutex.RUnlock()
+
+	result := make([]PoolSnapshot, 0, len(pools))
+	for name, pool := range pools {
+		state, counts := pool.Circuit.stateSnapshot()
+		total := counts.total()
+		var errorPercent float64
+		if total > 0 {
+			errorPercent = float64(counts.errors()) / float64(total) * 100
+		}
+		mean, p99 := h.latency.meanAndP99(name)
+
+		result = append(result, PoolSnapshot{
+			Name:              name,
+			CircuitState:      state.String(),
+			Requests:          total,
+			Successes:         counts.successes,
+			Failures:          counts.failures,
+			Timeouts:          counts.timeouts,
+			Rejections:        counts.rejections,
+			ShortCircuits:     counts.shortCircuits,
+			ErrorPercent:      errorPercent,
+			ExecutorPoolSize:  cap(pool.Executors),
+			ExecutorPoolInUse: cap(pool.Executors) - len(pool.Executors),
+			MeanLatencyMs:     float64(mean) / float64(time.Millisecond),
+			P99LatencyMs:      float64(p99) / float64(time.Millisecond),
+		})
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package hystrix
+
+import (
+	"errors"
+	"time"
+)
+
+// CommandGroup chains several Runners together as fallback tiers: the
+// primary Runner is tried first, and on error, timeout, pool rejection, or
+// an open circuit, the group advances to the next Runner in the chain
+// instead of immediately invoking a fallback. Only once every tier has been
+// exhausted does the last Runner's Fallback get called, with the error from
+// that final attempt.
+//
+// Each tier keeps its own ExecutorPool and Circuit (keyed by its
+// PoolName()), so a failing tier's circuit trips independently and its
+// Report* calls record which tier actually served a given request.
+type CommandGroup struct {
+	Runners []Runner
+	pools   []*ExecutorPool
+}
+
+// NewCommandGroup builds a CommandGroup that tries runners in order,
+// falling through to the next on failure.
+func NewCommandGroup(runners ...Runner) *CommandGroup {
+	group := &CommandGroup{Runners: runners}
+	for _, runner := range runners {
+		group.pools = append(group.pools, NewExecutorPool(runner.PoolName()))
+	}
+	return group
+}
+
+// Execute runs the command group synchronously, blocking until some tier's
+// result (or the last tier's fallback) is returned.
+func (group *CommandGroup) Execute() Result {
+	return <-group.Queue()
+}
+
+// Queue runs the command group asynchronously, immediately returning a
+// channel which the result (or final fallback) will be sent to.
+func (group *CommandGroup) Queue() chan Result {
+	channel := make(chan Result, 1)
+	go group.tryRun(channel)
+	return channel
+}
+
+func (group *CommandGroup) tryRun(valueChannel chan Result) {
+	defer close(valueChannel)
+
+	if len(group.Runners) == 0 {
+		valueChannel <- Result{Error: errors.New("command group has no runners")}
+		return
+	}
+
+	var lastErr error = errors.New("command group has no runners")
+	for i, runner := range group.Runners {
+		result, err, served := group.tryTier(group.pools[i], runner)
+		if served {
+			valueChannel <- result
+			return
+		}
+		lastErr = err
+	}
+
+	lastIndex := len(group.Runners) - 1
+	last := group.Runners[lastIndex]
+	lastPool := group.pools[lastIndex]
+
+	start := time.Now()
+	fallbackChannel := make(chan Result, 1)
+	go last.Fallback(lastErr, fallbackChannel)
+	result := <-fallbackChannel
+	if result.Error != nil {
+		publishMetric(MetricEvent{Type: EventFallbackFailure, PoolName: lastPool.Name, Latency: time.Since(start)})
+	} else {
+		publishMetric(MetricEvent{Type: EventFallbackSuccess, PoolName: lastPool.Name, Latency: time.Since(start)})
+	}
+	valueChannel <- result
+}
+
+// tryTier runs a single tier's Runner against its own pool and circuit,
+// reporting the outcome to that tier's Circuit and publishing it to every
+// registered MetricCollector under that tier's pool name, so it's visible
+// which tier actually served (or failed to serve) a given request. It never
+// calls Fallback - that's left to the caller once every tier has been
+// tried.
+func (group *CommandGroup) tryTier(pool *ExecutorPool, runner Runner) (result Result, err error, served bool) {
+	start := time.Now()
+	circuit := pool.Circuit
+	if circuit.IsOpen() {
+		circuit.ReportShortCircuit()
+		publishMetric(MetricEvent{Type: EventShortCircuit, PoolName: pool.Name, Latency: time.Since(start)})
+		return Result{}, errors.New("circuit open"), false
+	}
+
+	select {
+	case executor := <-pool.Executors:
+		defer func() {
+			pool.Executors <- executor
+		}()
+
+		resultChannel := make(chan Result, 1)
+		go runner.Run(resultChannel)
+
+		select {
+		case result := <-resultChannel:
+			if result.Error != nil {
+				circuit.ReportFailure()
+				publishMetric(MetricEvent{Type: EventFailure, PoolName: pool.Name, Latency: time.Since(start)})
+				return Result{}, result.Error, false
+			}
+			circuit.ReportSuccess()
+			publishMetric(MetricEvent{Type: EventSuccess, PoolName: pool.Name, Latency: time.Since(start)})
+			return result, nil, true
+		case <-time.After(runner.Timeout()):
+			circuit.ReportTimeout()
+			publishMetric(MetricEvent{Type: EventTimeout, PoolName: pool.Name, Latency: time.Since(start)})
+			return Result{}, errors.New("timeout"), false
+		}
+	default:
+		circuit.ReportRejection()
+		publishMetric(MetricEvent{Type: EventRejected, PoolName: pool.Name, Latency: time.Since(start)})
+		return Result{}, errors.New("executor pool full"), false
+	}
+}
@@ -0,0 +1,74 @@
+package hystrix
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type groupTestRunner struct {
+	name    string
+	timeout time.Duration
+	run     func(chan Result)
+}
+
+func (r *groupTestRunner) Run(result chan Result)                 { r.run(result) }
+func (r *groupTestRunner) Fallback(err error, result chan Result) { result <- Result{Error: err} }
+func (r *groupTestRunner) PoolName() string                       { return r.name }
+func (r *groupTestRunner) Timeout() time.Duration                 { return r.timeout }
+
+func TestCommandGroupNoRunnersFallsBackWithoutPanic(t *testing.T) {
+	group := NewCommandGroup()
+
+	result := group.Execute()
+
+	if result.Error == nil {
+		t.Fatal("expected an error result when the group has no runners, not a panic")
+	}
+}
+
+func TestCommandGroupAdvancesToNextTierOnFailure(t *testing.T) {
+	primary := &groupTestRunner{
+		name:    "group-test-advances-primary",
+		timeout: time.Second,
+		run: func(result chan Result) {
+			result <- Result{Error: errors.New("primary down")}
+		},
+	}
+	secondary := &groupTestRunner{
+		name:    "group-test-advances-secondary",
+		timeout: time.Second,
+		run: func(result chan Result) {
+			result <- Result{Value: "secondary"}
+		},
+	}
+
+	group := NewCommandGroup(primary, secondary)
+
+	result := group.Execute()
+
+	if result.Error != nil {
+		t.Fatalf("expected the secondary tier to serve the request, got error: %v", result.Error)
+	}
+	if result.Value != "secondary" {
+		t.Fatalf("expected the secondary tier's value, got %v", result.Value)
+	}
+}
+
+func TestCommandGroupFallsBackAfterAllTiersExhausted(t *testing.T) {
+	failing := &groupTestRunner{
+		name:    "group-test-exhausted",
+		timeout: time.Second,
+		run: func(result chan Result) {
+			result <- Result{Error: errors.New("down")}
+		},
+	}
+
+	group := NewCommandGroup(failing)
+
+	result := group.Execute()
+
+	if result.Error == nil {
+		t.Fatal("expected the last tier's Fallback to run once every tier is exhausted")
+	}
+}
@@ -0,0 +1,95 @@
+package hystrix
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	numBuckets     = 10
+	bucketDuration = time.Second
+)
+
+// bucket holds the raw event counts for a single one-second slice of a
+// rollingWindow.
+type bucket struct {
+	successes     uint64
+	failures      uint64
+	timeouts      uint64
+	rejections    uint64
+	shortCircuits uint64
+	cancellations uint64
+}
+
+func (b *bucket) total() uint64 {
+	return b.successes + b.failures + b.timeouts + b.rejections
+}
+
+func (b *bucket) errors() uint64 {
+	return b.failures + b.timeouts + b.rejections
+}
+
+// rollingWindow accumulates the last numBuckets one-second buckets of
+// command outcomes for a single Circuit.
+type rollingWindow struct {
+	mutex   sync.Mutex
+	buckets [numBuckets]bucket
+	index   int
+	lastAt  time.Time
+}
+
+func newRollingWindow() *rollingWindow {
+	return &rollingWindow{lastAt: time.Now()}
+}
+
+// advance rotates the window forward to now, clearing any buckets that have
+// aged out of the window. Callers must hold w.mutex.
+func (w *rollingWindow) advance(now time.Time) {
+	elapsed := int(now.Sub(w.lastAt) / bucketDuration)
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > numBuckets {
+		elapsed = numBuckets
+	}
+	for i := 0; i < elapsed; i++ {
+		w.index = (w.index + 1) % numBuckets
+		w.buckets[w.index] = bucket{}
+	}
+	w.lastAt = now
+}
+
+// record applies f to the current bucket, rotating the window first.
+func (w *rollingWindow) record(f func(b *bucket)) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.advance(time.Now())
+	f(&w.buckets[w.index])
+}
+
+// snapshot totals every live bucket in the window.
+func (w *rollingWindow) snapshot() bucket {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.advance(time.Now())
+
+	var total bucket
+	for _, b := range w.buckets {
+		total.successes += b.successes
+		total.failures += b.failures
+		total.timeouts += b.timeouts
+		total.rejections += b.rejections
+		total.shortCircuits += b.shortCircuits
+		total.cancellations += b.cancellations
+	}
+	return total
+}
+
+// reset clears the window, used when the circuit closes or re-opens.
+func (w *rollingWindow) reset() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.buckets = [numBuckets]bucket{}
+	w.index = 0
+	w.lastAt = time.Now()
+}
@@ -0,0 +1,58 @@
+package hystrix
+
+import (
+	"sync"
+	"time"
+)
+
+// MetricEventType identifies which command outcome a MetricEvent reports.
+type MetricEventType int
+
+const (
+	EventSuccess MetricEventType = iota
+	EventFailure
+	EventTimeout
+	EventShortCircuit
+	EventRejected
+	EventCancellation
+	EventFallbackSuccess
+	EventFallbackFailure
+)
+
+// MetricEvent is a single command outcome, published to every registered
+// MetricCollector as it happens.
+type MetricEvent struct {
+	Type     MetricEventType
+	PoolName string
+	Latency  time.Duration
+}
+
+// MetricCollector is implemented by anything that wants to observe every
+// command outcome across all pools - a Prometheus exporter, a statsd
+// client, or an in-memory collector such as the one backing
+// NewStreamHandler.
+type MetricCollector interface {
+	Update(event MetricEvent)
+}
+
+var (
+	collectorsMutex sync.RWMutex
+	collectors      []MetricCollector
+)
+
+// RegisterMetricCollector adds collector to the set notified of every
+// command outcome. It's typically called once at startup, and may be
+// called any number of times to fan events out to multiple collectors.
+func RegisterMetricCollector(collector MetricCollector) {
+	collectorsMutex.Lock()
+	defer collectorsMutex.Unlock()
+	collectors = append(collectors, collector)
+}
+
+func publishMetric(event MetricEvent) {
+	collectorsMutex.RLock()
+	defer collectorsMutex.RUnlock()
+	for _, collector := range collectors {
+		collector.Update(event)
+	}
+}
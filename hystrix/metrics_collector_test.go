@@ -0,0 +1,103 @@
+package hystrix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type metricsTestCollector struct {
+	mutex  sync.Mutex
+	events []MetricEvent
+}
+
+func (c *metricsTestCollector) Update(event MetricEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.events = append(c.events, event)
+}
+
+func (c *metricsTestCollector) hasEvent(poolName string, eventType MetricEventType) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, event := range c.events {
+		if event.PoolName == poolName && event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type metricsTestRunner struct {
+	name string
+	run  func(chan Result)
+}
+
+func (r *metricsTestRunner) Run(result chan Result)                 { r.run(result) }
+func (r *metricsTestRunner) Fallback(err error, result chan Result) { result <- Result{Error: err} }
+func (r *metricsTestRunner) PoolName() string                       { return r.name }
+func (r *metricsTestRunner) Timeout() time.Duration                 { return time.Second }
+
+func TestMetricCollectorObservesSuccessAndFallback(t *testing.T) {
+	collector := &metricsTestCollector{}
+	RegisterMetricCollector(collector)
+
+	successName := "metrics-test-success"
+	result := NewCommand(&metricsTestRunner{
+		name: successName,
+		run:  func(result chan Result) { result <- Result{Value: "ok"} },
+	}).Execute()
+	if result.Error != nil {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+	if !collector.hasEvent(successName, EventSuccess) {
+		t.Fatal("expected the registered MetricCollector to observe an EventSuccess")
+	}
+
+	failName := "metrics-test-fallback"
+	result = NewCommand(&metricsTestRunner{
+		name: failName,
+		run:  func(result chan Result) { result <- Result{Error: errors.New("boom")} },
+	}).Execute()
+	if result.Error == nil {
+		t.Fatal("expected the fallback's error to propagate")
+	}
+	if !collector.hasEvent(failName, EventFailure) {
+		t.Fatal("expected the registered MetricCollector to observe an EventFailure")
+	}
+	if !collector.hasEvent(failName, EventFallbackFailure) {
+		t.Fatal("expected the registered MetricCollector to observe the fallback's own EventFallbackFailure")
+	}
+}
+
+func TestStreamHandlerEmitsPoolSnapshots(t *testing.T) {
+	name := "metrics-test-stream"
+	NewCommand(&metricsTestRunner{
+		name: name,
+		run:  func(result chan Result) { result <- Result{Value: "ok"} },
+	}).Execute()
+
+	handler := NewStreamHandler(5 * time.Millisecond)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	request = request.WithContext(ctx)
+
+	handler.ServeHTTP(recorder, request)
+
+	body := recorder.Body.String()
+	if !strings.Contains(body, "data: ") {
+		t.Fatalf("expected at least one SSE frame, got body: %q", body)
+	}
+	if !strings.Contains(body, name) {
+		t.Fatalf("expected a snapshot for pool %q, got body: %q", name, body)
+	}
+}
@@ -0,0 +1,87 @@
+package hystrix
+
+import (
+	"context"
+	"sync"
+)
+
+// Executor represents a single slot within an ExecutorPool used to run a
+// Runner's work on its own goroutine.
+type Executor struct{}
+
+// Run invokes the command's Runner on this executor's goroutine, delivering
+// its result over the command's ResultChannel.
+func (e *Executor) Run(command *Command) {
+	command.Runner.Run(command.ResultChannel)
+}
+
+// ContextRunner is implemented by Runners that know how to abort their work
+// when ctx is cancelled. It is detected via type assertion so existing
+// Runners that only implement Run keep working unchanged.
+type ContextRunner interface {
+	RunContext(ctx context.Context, result chan Result)
+}
+
+// RunContext invokes the command's Runner on this executor's goroutine,
+// passing ctx through when the Runner implements ContextRunner. Runners
+// that don't implement it fall back to the plain Run, ignoring ctx.
+func (e *Executor) RunContext(ctx context.Context, command *Command) {
+	if runner, ok := command.Runner.(ContextRunner); ok {
+		runner.RunContext(ctx, command.ResultChannel)
+		return
+	}
+	command.Runner.Run(command.ResultChannel)
+}
+
+// ExecutorPool bounds the number of concurrent requests allowed to run a
+// given named command at once, and owns the Circuit that tracks that name's
+// rolling success/failure statistics. Under ThreadIsolation, Executors holds
+// goroutine slots; under SemaphoreIsolation it's used purely as a counting
+// semaphore, bounding concurrency without a dedicated goroutine pool (see
+// Command.trySemaphore for when it still runs inline versus on its own
+// goroutine).
+type ExecutorPool struct {
+	Name              string
+	Executors         chan *Executor
+	Circuit           *Circuit
+	IsolationStrategy IsolationStrategy
+}
+
+var (
+	poolMutex sync.RWMutex
+	pools     = make(map[string]*ExecutorPool)
+)
+
+// NewExecutorPool returns the ExecutorPool registered under name, creating
+// it the first time it is requested with MaxConcurrentRequests slots and
+// the IsolationStrategy from that name's CommandConfig (see
+// ConfigureCommand). Subsequent calls for the same name reuse the existing
+// pool (and its Circuit), so that statistics accumulate across every
+// Command built for that pool rather than resetting per-command.
+func NewExecutorPool(name string) *ExecutorPool {
+	poolMutex.RLock()
+	pool, ok := pools[name]
+	poolMutex.RUnlock()
+	if ok {
+		return pool
+	}
+
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+	if pool, ok = pools[name]; ok {
+		return pool
+	}
+
+	config := getSettings(name)
+	pool = &ExecutorPool{
+		Name:              name,
+		Executors:         make(chan *Executor, config.MaxConcurrentRequests),
+		Circuit:           newCircuit(name),
+		IsolationStrategy: config.IsolationStrategy,
+	}
+	for i := 0; i < config.MaxConcurrentRequests; i++ {
+		pool.Executors <- &Executor{}
+	}
+	pools[name] = pool
+	return pool
+}
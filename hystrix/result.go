@@ -0,0 +1,9 @@
+package hystrix
+
+// Result is what a Runner's Run and Fallback methods deliver over their
+// result channels: the value produced by the command (or its fallback),
+// and/or the error that caused a fallback to run.
+type Result struct {
+	Value interface{}
+	Error error
+}
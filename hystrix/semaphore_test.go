@@ -0,0 +1,71 @@
+package hystrix
+
+import (
+	"testing"
+	"time"
+)
+
+type semaphoreTestRunner struct {
+	name string
+	run  func(chan Result)
+}
+
+func (r *semaphoreTestRunner) Run(result chan Result)                 { r.run(result) }
+func (r *semaphoreTestRunner) Fallback(err error, result chan Result) { result <- Result{Error: err} }
+func (r *semaphoreTestRunner) PoolName() string                       { return r.name }
+func (r *semaphoreTestRunner) Timeout() time.Duration                 { return time.Second }
+
+func TestSemaphoreIsolationRejectsWhenPoolFull(t *testing.T) {
+	name := "semaphore-test-full"
+	ConfigureCommand(name, CommandConfig{
+		MaxConcurrentRequests: 1,
+		IsolationStrategy:     SemaphoreIsolation,
+	})
+
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	runner := &semaphoreTestRunner{
+		name: name,
+		run: func(result chan Result) {
+			close(holding)
+			<-release
+			result <- Result{Value: "done"}
+		},
+	}
+
+	first := NewCommand(runner).Queue()
+	<-holding // wait until the first call has taken the only semaphore slot
+
+	second := NewCommand(runner).Execute()
+	if second.Error == nil {
+		t.Fatal("expected the second call to be rejected while the semaphore is fully checked out")
+	}
+
+	close(release)
+	if result := <-first; result.Error != nil {
+		t.Fatalf("expected the first call to succeed once released, got error: %v", result.Error)
+	}
+}
+
+func TestSemaphoreIsolationSucceedsForBackgroundContext(t *testing.T) {
+	name := "semaphore-test-inline"
+	ConfigureCommand(name, CommandConfig{
+		MaxConcurrentRequests: 2,
+		IsolationStrategy:     SemaphoreIsolation,
+	})
+
+	runner := &semaphoreTestRunner{
+		name: name,
+		run: func(result chan Result) {
+			result <- Result{Value: "done"}
+		},
+	}
+
+	result := NewCommand(runner).Execute()
+	if result.Error != nil {
+		t.Fatalf("expected the background-context call to succeed inline, got error: %v", result.Error)
+	}
+	if result.Value != "done" {
+		t.Fatalf("expected the runner's value, got %v", result.Value)
+	}
+}
@@ -0,0 +1,105 @@
+package hystrix
+
+import (
+	"sync"
+	"time"
+)
+
+// IsolationStrategy selects how a pool bounds and runs concurrent requests.
+type IsolationStrategy int
+
+const (
+	// ThreadIsolation runs each request on its own goroutine drawn from a
+	// fixed-size ExecutorPool, enforcing Timeout() with a watchdog
+	// goroutine. This is the default and matches Hystrix's "thread"
+	// isolation.
+	ThreadIsolation IsolationStrategy = iota
+	// SemaphoreIsolation bounds concurrency via a counting semaphore
+	// instead of a dedicated goroutine pool. When the caller's context
+	// can never be cancelled (the common case), the request runs truly
+	// inline on the goroutine executing the command (the caller's own
+	// under Execute, the one Queue starts under Queue) - cheaper than ThreadIsolation
+	// for in-process callbacks - but it gives up the independent timeout
+	// watchdog that ThreadIsolation provides.
+	SemaphoreIsolation
+)
+
+// CommandConfig tunes the circuit breaker behaviour for a named pool. Any
+// zero-valued field is replaced by its default when the config is
+// registered via ConfigureCommand.
+type CommandConfig struct {
+	// RequestVolumeThreshold is the minimum number of requests that must
+	// be seen within the rolling window before the circuit is eligible to
+	// trip on error percentage.
+	RequestVolumeThreshold uint64
+	// ErrorPercentThreshold is the percentage (0-100) of failed requests
+	// in the rolling window that must be exceeded, once
+	// RequestVolumeThreshold is met, before the circuit opens.
+	ErrorPercentThreshold int
+	// SleepWindow is how long an open circuit waits before allowing a
+	// single probe request through to test for recovery.
+	SleepWindow time.Duration
+	// MaxConcurrentRequests bounds how many requests may run at once for
+	// the pool: goroutine slots under ThreadIsolation, semaphore tokens
+	// under SemaphoreIsolation.
+	MaxConcurrentRequests int
+	// IsolationStrategy selects thread vs semaphore isolation for the
+	// pool. Defaults to ThreadIsolation.
+	IsolationStrategy IsolationStrategy
+}
+
+const (
+	defaultRequestVolumeThreshold = 20
+	defaultErrorPercentThreshold  = 50
+	defaultSleepWindow            = 5 * time.Second
+	defaultMaxConcurrentRequests  = 10
+)
+
+var (
+	configMutex sync.RWMutex
+	configs     = make(map[string]CommandConfig)
+)
+
+// ConfigureCommand registers tunables for the named pool. It must be called
+// before that pool's first use (the first NewCommand, NewCommandGroup, or
+// NewExecutorPool call for poolName) to have any effect: the pool and its
+// Circuit read these settings once, at creation, and are then cached for
+// the life of the process, so a later ConfigureCommand call for an
+// already-created pool is silently ignored. Any zero-valued field on
+// config keeps its package default.
+func ConfigureCommand(poolName string, config CommandConfig) {
+	if config.RequestVolumeThreshold == 0 {
+		config.RequestVolumeThreshold = defaultRequestVolumeThreshold
+	}
+	if config.ErrorPercentThreshold == 0 {
+		config.ErrorPercentThreshold = defaultErrorPercentThreshold
+	}
+	if config.SleepWindow == 0 {
+		config.SleepWindow = defaultSleepWindow
+	}
+	if config.MaxConcurrentRequests == 0 {
+		config.MaxConcurrentRequests = defaultMaxConcurrentRequests
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	configs[poolName] = config
+}
+
+// getSettings returns the registered CommandConfig for poolName, or the
+// package defaults if ConfigureCommand was never called for it.
+func getSettings(poolName string) CommandConfig {
+	configMutex.RLock()
+	config, ok := configs[poolName]
+	configMutex.RUnlock()
+	if ok {
+		return config
+	}
+
+	return CommandConfig{
+		RequestVolumeThreshold: defaultRequestVolumeThreshold,
+		ErrorPercentThreshold:  defaultErrorPercentThreshold,
+		SleepWindow:            defaultSleepWindow,
+		MaxConcurrentRequests:  defaultMaxConcurrentRequests,
+	}
+}